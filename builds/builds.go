@@ -0,0 +1,70 @@
+// Package builds creates and tracks container images built from registered
+// Simplex components.
+package builds
+
+import (
+	"context"
+	"database/sql"
+	"io"
+
+	"github.com/simiotics/simplex/components"
+	"github.com/simiotics/simplex/runtime"
+)
+
+// Build is a record of an image built for a component, or pulled from a
+// registry in lieu of a local build.
+type Build struct {
+	ID          string
+	ComponentID string
+	RemoteRef   string
+	Digest      string
+}
+
+// CreateBuild builds an image for the given component using backend and
+// records the resulting build against the state database. The build is
+// tagged with a generated ID as well as a "latest" tag for the component.
+func CreateBuild(ctx context.Context, db *sql.DB, backend runtime.Backend, logs io.Writer, componentID string) (Build, error) {
+	component, err := components.GetComponent(db, componentID)
+	if err != nil {
+		return Build{}, err
+	}
+
+	buildID := generateBuildID(componentID)
+	latestTag := latestTagFor(buildID)
+
+	if _, err := backend.BuildImage(ctx, runtime.BuildOptions{
+		ContextDir: component.ComponentPath,
+		Dockerfile: "Dockerfile",
+		Tags:       []string{buildID, latestTag},
+	}, logs); err != nil {
+		return Build{}, err
+	}
+
+	build := Build{ID: buildID, ComponentID: componentID}
+
+	_, err = db.Exec(
+		"INSERT INTO builds (id, component_id) VALUES (?, ?)",
+		build.ID, build.ComponentID,
+	)
+	if err != nil {
+		return Build{}, err
+	}
+
+	return build, nil
+}
+
+// GetBuild retrieves a previously recorded build by ID. Builds created via
+// PullBuild have no ComponentID, since they were not built from a locally
+// registered component.
+func GetBuild(db *sql.DB, id string) (Build, error) {
+	var build Build
+	var componentID, remoteRef, digest sql.NullString
+	row := db.QueryRow("SELECT id, component_id, remote_ref, digest FROM builds WHERE id = ?", id)
+	if err := row.Scan(&build.ID, &componentID, &remoteRef, &digest); err != nil {
+		return Build{}, err
+	}
+	build.ComponentID = componentID.String
+	build.RemoteRef = remoteRef.String
+	build.Digest = digest.String
+	return build, nil
+}
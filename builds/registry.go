@@ -0,0 +1,204 @@
+package builds
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dockerTypes "github.com/docker/docker/api/types"
+
+	"github.com/simiotics/simplex/runtime"
+)
+
+// Mirrors lists registry hosts to try, in order, before falling back to the
+// canonical registry named in a pull's ref. A mirror is only consulted for
+// pulls; pushes always go to the ref's own registry. Callers (typically the
+// CLI, sourcing this from config or a flag) are expected to set this once at
+// startup.
+var Mirrors []string
+
+// PushBuild tags build buildID as ref and pushes it to ref's registry,
+// recording the resulting digest against the build.
+func PushBuild(ctx context.Context, db *sql.DB, backend runtime.Backend, buildID, ref string) error {
+	if err := backend.TagImage(ctx, buildID, ref); err != nil {
+		return fmt.Errorf("could not tag %s as %s: %w", buildID, ref, err)
+	}
+
+	auth, err := authForRef(ref)
+	if err != nil {
+		return err
+	}
+
+	digest, err := backend.PushImage(ctx, ref, auth, ioutil.Discard)
+	if err != nil {
+		return fmt.Errorf("could not push %s: %w", ref, err)
+	}
+
+	_, err = db.Exec("UPDATE builds SET remote_ref = ?, digest = ? WHERE id = ?", ref, digest, buildID)
+	return err
+}
+
+// PullBuild pulls ref, trying each configured mirror before ref's own
+// registry, and records it as a build against the state database.
+func PullBuild(ctx context.Context, db *sql.DB, backend runtime.Backend, ref string) (Build, error) {
+	auth, err := authForRef(ref)
+	if err != nil {
+		return Build{}, err
+	}
+
+	candidates := append(mirroredRefs(ref), ref)
+
+	var image runtime.Image
+	var pulled string
+	var pullErr error
+	for _, candidate := range candidates {
+		image, pullErr = backend.PullImage(ctx, candidate, auth, ioutil.Discard)
+		if pullErr == nil {
+			pulled = candidate
+			break
+		}
+	}
+	if pullErr != nil {
+		return Build{}, fmt.Errorf("could not pull %s from any of %v: %w", ref, candidates, pullErr)
+	}
+
+	// A pull from a mirror lands under the mirror's own reference; retag it
+	// to the canonical ref so callers can resolve either a local tag or the
+	// ref they asked to pull.
+	if pulled != ref {
+		if err := backend.TagImage(ctx, pulled, ref); err != nil {
+			return Build{}, fmt.Errorf("could not tag mirrored pull %s as %s: %w", pulled, ref, err)
+		}
+	}
+
+	build := Build{ID: ref}
+	_, err = db.Exec(
+		"INSERT INTO builds (id, component_id, remote_ref, digest) VALUES (?, NULL, ?, ?)",
+		build.ID, ref, image.Digest,
+	)
+	if err != nil {
+		return Build{}, err
+	}
+
+	return build, nil
+}
+
+// ResolveRef returns the image reference executions.Execute should hand to
+// the backend for buildID: the build's own ID if the backend already has it
+// (the common case, for a build produced locally), otherwise the remote
+// digest recorded against it by PushBuild, pulled on demand.
+func ResolveRef(ctx context.Context, db *sql.DB, backend runtime.Backend, buildID string) (string, error) {
+	if _, err := backend.InspectImage(ctx, buildID); err == nil {
+		return buildID, nil
+	}
+
+	build, err := GetBuild(db, buildID)
+	if err != nil {
+		return "", fmt.Errorf("build %s not present locally and not found in state: %w", buildID, err)
+	}
+	if build.Digest == "" {
+		return "", fmt.Errorf("build %s not present locally and has no recorded remote digest", buildID)
+	}
+
+	ref := build.RemoteRef + "@" + build.Digest
+	if _, err := PullBuild(ctx, db, backend, ref); err != nil {
+		return "", fmt.Errorf("could not pull %s to resolve build %s: %w", ref, buildID, err)
+	}
+	return ref, nil
+}
+
+// mirroredRefs rewrites ref's registry host to each configured mirror, in
+// order. refs with no registry host (Docker Hub shorthand) are left to the
+// canonical registry only, since a mirror cannot be inferred for them.
+func mirroredRefs(ref string) []string {
+	host, rest, ok := strings.Cut(ref, "/")
+	if !ok || !strings.ContainsAny(host, ".:") {
+		return nil
+	}
+
+	mirrored := make([]string, 0, len(Mirrors))
+	for _, mirror := range Mirrors {
+		mirrored = append(mirrored, mirror+"/"+rest)
+	}
+	return mirrored
+}
+
+// dockerConfig mirrors the handful of fields of ~/.docker/config.json that
+// Simplex needs in order to authenticate pushes and pulls.
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth string `json:"auth"`
+	} `json:"auths"`
+}
+
+// dockerHubAuthKey is the host key the Docker CLI writes to
+// ~/.docker/config.json for Docker Hub, which does not match the registry
+// host ("registry-1.docker.io") that Hub image refs actually pull from.
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// authForRef loads registry credentials for ref's registry host out of
+// ~/.docker/config.json and encodes them the way runtime.Backend expects. A
+// registry with no matching entry yields an empty RegistryAuth, which
+// backends treat as an anonymous pull/push.
+func authForRef(ref string) (runtime.RegistryAuth, error) {
+	host, _, ok := strings.Cut(ref, "/")
+	lookupHost := host
+	if !ok || !strings.ContainsAny(host, ".:") {
+		host = "registry-1.docker.io"
+		lookupHost = dockerHubAuthKey
+	}
+
+	configPath, err := dockerConfigPath()
+	if err != nil {
+		return runtime.RegistryAuth{}, err
+	}
+
+	raw, err := ioutil.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return runtime.RegistryAuth{}, nil
+	}
+	if err != nil {
+		return runtime.RegistryAuth{}, fmt.Errorf("could not read %s: %w", configPath, err)
+	}
+
+	var config dockerConfig
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return runtime.RegistryAuth{}, fmt.Errorf("could not parse %s: %w", configPath, err)
+	}
+
+	entry, ok := config.Auths[lookupHost]
+	if !ok {
+		return runtime.RegistryAuth{}, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+	if err != nil {
+		return runtime.RegistryAuth{}, fmt.Errorf("could not decode auth for %s: %w", host, err)
+	}
+
+	username, password, _ := strings.Cut(string(decoded), ":")
+	authConfig, err := json.Marshal(dockerTypes.AuthConfig{
+		Username:      username,
+		Password:      password,
+		ServerAddress: host,
+	})
+	if err != nil {
+		return runtime.RegistryAuth{}, err
+	}
+
+	return runtime.RegistryAuth{Encoded: base64.URLEncoding.EncodeToString(authConfig)}, nil
+}
+
+func dockerConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".docker", "config.json"), nil
+}
@@ -0,0 +1,23 @@
+package builds
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// generateBuildID produces a unique image tag for a build of the given
+// component, of the form "<componentID>:<unix-timestamp-in-nanoseconds>".
+func generateBuildID(componentID string) string {
+	return fmt.Sprintf("%s:%d", componentID, time.Now().UnixNano())
+}
+
+// latestTagFor rewrites a build ID's tag portion to "latest", e.g.
+// "my-component:12345" becomes "my-component:latest".
+func latestTagFor(buildID string) string {
+	tagParts := strings.Split(buildID, ":")
+	if len(tagParts) > 1 {
+		tagParts[len(tagParts)-1] = "latest"
+	}
+	return strings.Join(tagParts, ":")
+}
@@ -0,0 +1,35 @@
+// Package cmd implements the subcommands of the simplex CLI.
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/simiotics/simplex/executions"
+	"github.com/simiotics/simplex/runtime"
+)
+
+// ExecLogs implements `simplex exec logs <id>`: it streams the named
+// execution's stdout/stderr to the current process's own stdout/stderr
+// until the execution exits.
+func ExecLogs(ctx context.Context, backend runtime.Backend, executionID string) error {
+	return executions.Attach(ctx, backend, executionID, os.Stdout, os.Stderr)
+}
+
+// ExecStatus implements `simplex exec status <id>`: it prints the most
+// recently observed lifecycle state of the named execution.
+func ExecStatus(db *sql.DB, executionID string) error {
+	status, err := executions.Status(db, executionID)
+	if err != nil {
+		return fmt.Errorf("could not retrieve status for execution %s: %w", executionID, err)
+	}
+
+	if status.Type == executions.Exited {
+		fmt.Printf("%s: %s (exit code %d)\n", executionID, status.Type, status.Code)
+	} else {
+		fmt.Printf("%s: %s\n", executionID, status.Type)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Package components manages the registration of Simplex components -
+// reusable units of work whose specifications describe how they are built
+// and run.
+package components
+
+import "database/sql"
+
+// ComponentType enumerates the kinds of component Simplex knows how to
+// register. Currently Task is the only supported type, but the type is kept
+// distinct from a bare string so that new component kinds (e.g. long-running
+// services) can be added without touching every call site.
+type ComponentType string
+
+// Task is a component that runs to completion and exits, as opposed to a
+// long-running service.
+const Task ComponentType = "task"
+
+// Component is a registered unit of work tracked in the state database.
+type Component struct {
+	ID                string
+	ComponentType     ComponentType
+	ComponentPath     string
+	SpecificationPath string
+}
+
+// AddComponent registers a new component against the state database. id must
+// be unique among registered components. componentPath is the directory
+// containing the component's build context and specificationPath points at
+// the component.json specification within it.
+func AddComponent(db *sql.DB, id string, componentType ComponentType, componentPath, specificationPath string) (Component, error) {
+	component := Component{
+		ID:                id,
+		ComponentType:     componentType,
+		ComponentPath:     componentPath,
+		SpecificationPath: specificationPath,
+	}
+
+	_, err := db.Exec(
+		"INSERT INTO components (id, component_type, component_path, specification_path) VALUES (?, ?, ?, ?)",
+		component.ID, string(component.ComponentType), component.ComponentPath, component.SpecificationPath,
+	)
+	if err != nil {
+		return Component{}, err
+	}
+
+	return component, nil
+}
+
+// GetComponent retrieves a previously registered component by ID.
+func GetComponent(db *sql.DB, id string) (Component, error) {
+	var component Component
+	var componentType string
+	row := db.QueryRow("SELECT id, component_type, component_path, specification_path FROM components WHERE id = ?", id)
+	if err := row.Scan(&component.ID, &componentType, &component.ComponentPath, &component.SpecificationPath); err != nil {
+		return Component{}, err
+	}
+	component.ComponentType = ComponentType(componentType)
+	return component, nil
+}
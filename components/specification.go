@@ -0,0 +1,42 @@
+package components
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Mountpoint describes a single bind mount a component's run step expects to
+// have available inside its container, along with the default mount policy
+// callers should apply unless they have a specific reason to override it.
+type Mountpoint struct {
+	Mountpoint string `json:"mountpoint"`
+	// ReadOnly marks this mountpoint as one the component only reads from.
+	ReadOnly bool `json:"read_only"`
+	// SELinuxRelabel is "", "shared" or "private", matching
+	// runtime.SELinuxRelabel's values. It is kept as a plain string here,
+	// rather than that type, so that components does not need to depend on
+	// the runtime package merely to describe a mount policy.
+	SELinuxRelabel string `json:"selinux_relabel"`
+}
+
+// Run describes how a component's image should be executed: the environment
+// variables it expects and the mountpoints it reads from or writes to.
+type Run struct {
+	Env         map[string]string `json:"env"`
+	Mountpoints []Mountpoint      `json:"mountpoints"`
+}
+
+// Specification is the parsed form of a component's component.json file.
+type Specification struct {
+	Run Run `json:"run"`
+}
+
+// ReadSingleSpecification parses a single-component specification document.
+func ReadSingleSpecification(reader io.Reader) (Specification, error) {
+	var specification Specification
+	decoder := json.NewDecoder(reader)
+	if err := decoder.Decode(&specification); err != nil {
+		return Specification{}, err
+	}
+	return specification, nil
+}
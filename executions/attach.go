@@ -0,0 +1,14 @@
+package executions
+
+import (
+	"context"
+	"io"
+
+	"github.com/simiotics/simplex/runtime"
+)
+
+// Attach streams executionID's stdout and stderr to the given writers,
+// demultiplexed, until the container exits or ctx is canceled.
+func Attach(ctx context.Context, backend runtime.Backend, executionID string, stdout, stderr io.Writer) error {
+	return backend.AttachContainer(ctx, executionID, stdout, stderr)
+}
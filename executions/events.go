@@ -0,0 +1,131 @@
+package executions
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/simiotics/simplex/runtime"
+)
+
+// EventType identifies a lifecycle transition reported by Events.
+type EventType string
+
+const (
+	// Created fires once, when the execution's container is created.
+	Created EventType = "created"
+	// Started fires once, when the execution's container begins running.
+	Started EventType = "started"
+	// Exited fires once, when the execution's container exits. Event.Code
+	// is only meaningful for this EventType.
+	Exited EventType = "exited"
+)
+
+// Event is a single lifecycle transition for an execution.
+type Event struct {
+	Type EventType
+	Code int64
+}
+
+// Events streams lifecycle events for executionID, persisting each one into
+// the execution_states table as it is observed so that state transitions
+// remain queryable after the fact (see Status). The returned channel is
+// closed once the execution exits or ctx is canceled.
+func Events(ctx context.Context, db *sql.DB, backend runtime.Backend, executionID string) (<-chan Event, error) {
+	containerEvents, err := backend.ContainerEvents(ctx, executionID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+
+		emitted := map[EventType]bool{}
+		emit := func(event Event) {
+			emitted[event.Type] = true
+			recordExecutionState(db, executionID, event)
+			events <- event
+		}
+
+		// A container that ran and exited before this call subscribed to the
+		// backend's live event stream will never produce a Created/Started/
+		// Exited sequence on containerEvents, so race the stream against
+		// WaitContainer and synthesize whatever transitions were missed once
+		// the container is known to have exited.
+		exited := make(chan int64, 1)
+		go func() {
+			if code, err := backend.WaitContainer(ctx, executionID); err == nil {
+				exited <- code
+			}
+		}()
+
+		for {
+			select {
+			case containerEvent, ok := <-containerEvents:
+				if !ok {
+					containerEvents = nil
+					continue
+				}
+				event := translateContainerEvent(containerEvent)
+				emit(event)
+				if event.Type == Exited {
+					return
+				}
+			case code := <-exited:
+				if !emitted[Created] {
+					emit(Event{Type: Created})
+				}
+				if !emitted[Started] {
+					emit(Event{Type: Started})
+				}
+				if !emitted[Exited] {
+					emit(Event{Type: Exited, Code: code})
+				}
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Status returns the most recently recorded lifecycle event for
+// executionID, as persisted by Events.
+func Status(db *sql.DB, executionID string) (Event, error) {
+	var eventType string
+	var code sql.NullInt64
+	row := db.QueryRow(
+		"SELECT state, exit_code FROM execution_states WHERE execution_id = ? ORDER BY id DESC LIMIT 1",
+		executionID,
+	)
+	if err := row.Scan(&eventType, &code); err != nil {
+		return Event{}, err
+	}
+	return Event{Type: EventType(eventType), Code: code.Int64}, nil
+}
+
+func translateContainerEvent(containerEvent runtime.ContainerEvent) Event {
+	switch containerEvent.Status {
+	case runtime.ContainerCreated:
+		return Event{Type: Created}
+	case runtime.ContainerStarted:
+		return Event{Type: Started}
+	case runtime.ContainerExited:
+		return Event{Type: Exited, Code: containerEvent.ExitCode}
+	default:
+		return Event{}
+	}
+}
+
+func recordExecutionState(db *sql.DB, executionID string, event Event) {
+	var code sql.NullInt64
+	if event.Type == Exited {
+		code = sql.NullInt64{Int64: event.Code, Valid: true}
+	}
+	db.Exec(
+		"INSERT INTO execution_states (execution_id, state, exit_code) VALUES (?, ?, ?)",
+		executionID, string(event.Type), code,
+	)
+}
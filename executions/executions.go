@@ -0,0 +1,135 @@
+// Package executions runs container images built from Simplex components
+// and tracks their lifecycle in the state database.
+package executions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/simiotics/simplex/builds"
+	"github.com/simiotics/simplex/components"
+	"github.com/simiotics/simplex/runtime"
+)
+
+// Execution is a record of a single container run of a build.
+type Execution struct {
+	ID      string
+	BuildID string
+}
+
+// Mount describes a single bind mount to attach to an execution's container,
+// carrying the same SELinux relabeling and read-only semantics as
+// runtime.Mount so that callers don't need to import the runtime package
+// just to build a mount list.
+type Mount struct {
+	Source         string
+	Target         string
+	ReadOnly       bool
+	SELinuxRelabel runtime.SELinuxRelabel
+}
+
+// MountFor builds the Mount for binding source onto mountpoint, applying the
+// mountpoint's default read-only/SELinux relabel policy. Callers with a
+// reason to deviate from that default can build a Mount by hand instead.
+func MountFor(source string, mountpoint components.Mountpoint) Mount {
+	return Mount{
+		Source:         source,
+		Target:         mountpoint.Mountpoint,
+		ReadOnly:       mountpoint.ReadOnly,
+		SELinuxRelabel: runtime.SELinuxRelabel(mountpoint.SELinuxRelabel),
+	}
+}
+
+// Execute creates and starts a container from buildID on backend, binding
+// mounts into it. command, if non-empty, is passed as the container's single
+// command argument, overriding the image's default command; the image's
+// entrypoint is left as is.
+func Execute(ctx context.Context, db *sql.DB, backend runtime.Backend, buildID string, command string, mounts []Mount) (Execution, error) {
+	imageRef, err := builds.ResolveRef(ctx, db, backend, buildID)
+	if err != nil {
+		return Execution{}, err
+	}
+
+	env, err := envForBuild(db, buildID)
+	if err != nil {
+		return Execution{}, err
+	}
+
+	runtimeMounts := make([]runtime.Mount, 0, len(mounts))
+	for _, mount := range mounts {
+		runtimeMounts = append(runtimeMounts, runtime.Mount{
+			Source:         mount.Source,
+			Target:         mount.Target,
+			ReadOnly:       mount.ReadOnly,
+			SELinuxRelabel: mount.SELinuxRelabel,
+		})
+	}
+
+	config := runtime.ContainerConfig{
+		Image:  imageRef,
+		Env:    env,
+		Mounts: runtimeMounts,
+	}
+	if command != "" {
+		config.Command = []string{command}
+	}
+
+	containerID, err := backend.CreateContainer(ctx, config)
+	if err != nil {
+		return Execution{}, fmt.Errorf("could not create container for build %s: %w", buildID, err)
+	}
+
+	if err := backend.StartContainer(ctx, containerID); err != nil {
+		return Execution{}, fmt.Errorf("could not start container %s: %w", containerID, err)
+	}
+
+	execution := Execution{ID: containerID, BuildID: buildID}
+
+	_, err = db.Exec(
+		"INSERT INTO executions (id, build_id) VALUES (?, ?)",
+		execution.ID, execution.BuildID,
+	)
+	if err != nil {
+		return Execution{}, err
+	}
+
+	return execution, nil
+}
+
+// envForBuild loads the environment variables buildID's component declares
+// for its run step, translated into "KEY=VALUE" strings for
+// runtime.ContainerConfig.Env. Builds pulled from a registry via
+// builds.PullBuild have no component on record and run with no environment.
+func envForBuild(db *sql.DB, buildID string) ([]string, error) {
+	build, err := builds.GetBuild(db, buildID)
+	if err != nil {
+		return nil, err
+	}
+	if build.ComponentID == "" {
+		return nil, nil
+	}
+
+	component, err := components.GetComponent(db, build.ComponentID)
+	if err != nil {
+		return nil, err
+	}
+
+	specFile, err := os.Open(component.SpecificationPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open specification for component %s: %w", component.ID, err)
+	}
+	defer specFile.Close()
+
+	specification, err := components.ReadSingleSpecification(specFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse specification for component %s: %w", component.ID, err)
+	}
+
+	env := make([]string, 0, len(specification.Run.Env))
+	for key, value := range specification.Run.Env {
+		env = append(env, key+"="+value)
+	}
+	return env, nil
+}
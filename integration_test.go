@@ -10,11 +10,10 @@ import (
 	"strings"
 	"testing"
 
-	dockerTypes "github.com/docker/docker/api/types"
-
 	"github.com/simiotics/simplex/builds"
 	"github.com/simiotics/simplex/components"
 	"github.com/simiotics/simplex/executions"
+	"github.com/simiotics/simplex/runtime"
 	"github.com/simiotics/simplex/state"
 )
 
@@ -60,9 +59,10 @@ func TestSingleComponent(t *testing.T) {
 	}
 
 	dockerClient := generateDockerClient()
+	backend := runtime.NewDockerBackend(dockerClient)
 	ctx := context.Background()
 
-	build, err := builds.CreateBuild(ctx, db, dockerClient, ioutil.Discard, component.ID)
+	build, err := builds.CreateBuild(ctx, db, backend, ioutil.Discard, component.ID)
 	if err != nil {
 		t.Fatalf("Error building image for component: %s", err.Error())
 	}
@@ -70,14 +70,14 @@ func TestSingleComponent(t *testing.T) {
 		t.Fatalf("Unexpected component ID on build: expected=%s, actual=%s", component.ID, build.ComponentID)
 	}
 
-	imageInfo, _, err := dockerClient.ImageInspectWithRaw(ctx, build.ID)
+	imageInfo, err := backend.InspectImage(ctx, build.ID)
 	if err != nil {
 		t.Fatalf("Could not inspect image with tag: %s", build.ID)
 	}
-	defer dockerClient.ImageRemove(ctx, imageInfo.ID, dockerTypes.ImageRemoveOptions{Force: true, PruneChildren: true})
+	defer backend.RemoveImage(ctx, imageInfo.ID, true)
 
 	buildTags := map[string]bool{}
-	for _, tag := range imageInfo.RepoTags {
+	for _, tag := range imageInfo.Tags {
 		buildTags[tag] = true
 	}
 
@@ -94,7 +94,7 @@ func TestSingleComponent(t *testing.T) {
 		t.Fatalf("Expected tag (%s) was not registered against docker daemon", latestTag)
 	}
 
-	mounts := map[string]string{}
+	mounts := []executions.Mount{}
 	specFile, err := os.Open(specificationPath)
 	if err != nil {
 		t.Fatalf("Error opening specification file (%s): %s", specificationPath, err.Error())
@@ -109,26 +109,43 @@ func TestSingleComponent(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Error creating temporary file to mount onto container path %s: %s", mountpoint.Mountpoint, err.Error())
 		}
-		mounts[sourceFile.Name()] = mountpoint.Mountpoint
+		mounts = append(mounts, executions.MountFor(sourceFile.Name(), mountpoint))
 		defer os.Remove(sourceFile.Name())
 	}
 
-	execution, err := executions.Execute(ctx, db, dockerClient, build.ID, "", mounts)
+	execution, err := executions.Execute(ctx, db, backend, build.ID, "", mounts)
 	if err != nil {
 		t.Fatalf("Error executing build (%s): %s", build.ID, err.Error())
 	}
-	exitCode, err := dockerClient.ContainerWait(ctx, execution.ID)
+	defer backend.RemoveContainer(ctx, execution.ID, true)
+
+	executionEvents, err := executions.Events(ctx, db, backend, execution.ID)
+	if err != nil {
+		t.Fatalf("Error subscribing to execution events: %s", err.Error())
+	}
+
+	var exitEvent executions.Event
+	for event := range executionEvents {
+		if event.Type == executions.Exited {
+			exitEvent = event
+			break
+		}
+	}
+	if exitEvent.Code != 0 {
+		t.Fatalf("Received non-zero exit code (%d) from container (ID: %s)", exitEvent.Code, execution.ID)
+	}
+
+	status, err := executions.Status(db, execution.ID)
 	if err != nil {
-		t.Fatalf("Error waiting for container (ID: %s) to exit: %s", execution.ID, err.Error())
+		t.Fatalf("Error retrieving execution status (ID: %s): %s", execution.ID, err.Error())
 	}
-	if exitCode != 0 {
-		t.Fatalf("Received non-zero exit code (%d) from container (ID: %s)", exitCode, execution.ID)
+	if status.Type != executions.Exited {
+		t.Fatalf("Unexpected execution status: expected=%s, actual=%s", executions.Exited, status.Type)
 	}
-	defer dockerClient.ContainerRemove(ctx, execution.ID, dockerTypes.ContainerRemoveOptions{})
 
 	inverseMounts := map[string]string{}
-	for source, target := range mounts {
-		inverseMounts[target] = source
+	for _, mount := range mounts {
+		inverseMounts[mount.Target] = mount.Source
 	}
 	outfile, err := os.Open(inverseMounts["/simplex/outputs/outputs.txt"])
 	if err != nil {
@@ -158,6 +175,4 @@ func TestSingleComponent(t *testing.T) {
 	if terminating > 1 {
 		t.Fatalf("Too many terminating newlines in output file: %d", terminating)
 	}
-
-	// TODO(nkashy1): Implement execution state management and add those functions into this test
 }
\ No newline at end of file
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+
+	dockerClient "github.com/docker/docker/client"
+
+	"github.com/simiotics/simplex/cmd"
+	"github.com/simiotics/simplex/runtime"
+	"github.com/simiotics/simplex/state"
+)
+
+// generateDockerClient constructs a Docker client from the ambient
+// environment (DOCKER_HOST, DOCKER_CERT_PATH, etc). It is kept separate from
+// runtime.New so that callers needing a raw *client.Client (e.g. tests that
+// reach past the Backend abstraction to assert against the daemon directly)
+// do not have to unwrap a runtime.Backend to get one.
+func generateDockerClient() *dockerClient.Client {
+	client, err := dockerClient.NewClientWithOpts(dockerClient.FromEnv, dockerClient.WithAPIVersionNegotiation())
+	if err != nil {
+		panic(fmt.Sprintf("could not create docker client: %s", err.Error()))
+	}
+	return client
+}
+
+func main() {
+	runtimeFlag := flag.String("runtime", "", fmt.Sprintf("Container runtime backend to use (docker|podman); defaults to $%s, then docker", runtime.EnvVar))
+	flag.Parse()
+
+	ctx := context.Background()
+
+	backend, err := runtime.New(ctx, runtime.Resolve(*runtimeFlag))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	args := flag.Args()
+	if len(args) == 3 && args[0] == "exec" {
+		executionID := args[2]
+		switch args[1] {
+		case "logs":
+			err = cmd.ExecLogs(ctx, backend, executionID)
+		case "status":
+			var db *sql.DB
+			db, err = openStateDB()
+			if err == nil {
+				defer db.Close()
+				err = cmd.ExecStatus(db, executionID)
+			}
+		default:
+			err = fmt.Errorf("unknown exec subcommand: %s", args[1])
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	flag.Usage()
+}
+
+// openStateDB opens the sqlite database in Simplex's default state
+// directory, initializing the directory first if it does not yet exist.
+func openStateDB() (*sql.DB, error) {
+	stateDir, err := state.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := state.Init(stateDir); err != nil {
+		return nil, err
+	}
+	return sql.Open("sqlite3", path.Join(stateDir, state.DBFileName))
+}
@@ -0,0 +1,144 @@
+// Package runtime abstracts the container runtime operations Simplex needs
+// (image builds, container lifecycle, log streaming) behind a single
+// Backend interface so that the rest of Simplex does not need to know
+// whether it is talking to a Docker daemon or a Podman service.
+package runtime
+
+import (
+	"context"
+	"io"
+)
+
+// SELinuxRelabel selects how a bind mount's SELinux label should be
+// rewritten on hosts with SELinux enforcing.
+type SELinuxRelabel string
+
+const (
+	// SELinuxRelabelNone leaves the mount's label untouched.
+	SELinuxRelabelNone SELinuxRelabel = ""
+	// SELinuxRelabelShared (":z") relabels the source so it is shared
+	// across multiple containers.
+	SELinuxRelabelShared SELinuxRelabel = "shared"
+	// SELinuxRelabelPrivate (":Z") relabels the source so only this
+	// container can access it.
+	SELinuxRelabelPrivate SELinuxRelabel = "private"
+)
+
+// Mount describes a single bind mount to attach to a container.
+type Mount struct {
+	// Source is a path on the host.
+	Source string
+	// Target is the path inside the container that Source is mounted onto.
+	Target string
+	// ReadOnly mounts Source read-only inside the container.
+	ReadOnly bool
+	// SELinuxRelabel requests the bind mount be relabeled for SELinux.
+	SELinuxRelabel SELinuxRelabel
+}
+
+// BuildOptions describes an image build request.
+type BuildOptions struct {
+	// ContextDir is the build context directory, passed to the backend as a
+	// tar stream.
+	ContextDir string
+	// Dockerfile is the path to the Dockerfile, relative to ContextDir.
+	Dockerfile string
+	// Tags are the image references to apply to the resulting image.
+	Tags []string
+}
+
+// ContainerConfig describes a container creation request.
+type ContainerConfig struct {
+	Image   string
+	Env     []string
+	Mounts  []Mount
+	Command []string
+}
+
+// Image describes an image known to the backend.
+type Image struct {
+	ID     string
+	Tags   []string
+	Digest string
+}
+
+// RegistryAuth carries the credentials needed to push or pull against a
+// registry, pre-encoded the way the backend expects (for Docker, the
+// base64-encoded JSON of a types.AuthConfig; the Docker and Podman
+// bindings both accept this representation unchanged).
+type RegistryAuth struct {
+	Encoded string
+}
+
+// ContainerEventStatus is the lifecycle status reported for a container by
+// the backend's event stream.
+type ContainerEventStatus string
+
+const (
+	// ContainerCreated fires once, when the container is created.
+	ContainerCreated ContainerEventStatus = "created"
+	// ContainerStarted fires once, when the container begins running.
+	ContainerStarted ContainerEventStatus = "started"
+	// ContainerExited fires once, when the container's process exits.
+	// ContainerEvent.ExitCode is only meaningful for this status.
+	ContainerExited ContainerEventStatus = "exited"
+)
+
+// ContainerEvent is a single lifecycle transition reported by a backend's
+// event stream for one container.
+type ContainerEvent struct {
+	Status   ContainerEventStatus
+	ExitCode int64
+}
+
+// Backend abstracts the container runtime operations Simplex depends on.
+// Implementations exist for Docker (backed by the Docker Engine API) and
+// Podman (backed by the libpod REST API via bindings/).
+type Backend interface {
+	// BuildImage builds an image from the given build context. The Docker
+	// backend streams build output to logs; the Podman backend does not
+	// write to logs.
+	BuildImage(ctx context.Context, opts BuildOptions, logs io.Writer) (Image, error)
+	// InspectImage returns metadata about a previously built or pulled image.
+	InspectImage(ctx context.Context, ref string) (Image, error)
+	// RemoveImage removes an image known to the backend.
+	RemoveImage(ctx context.Context, ref string, force bool) error
+	// TagImage applies an additional reference to an already-known image.
+	TagImage(ctx context.Context, source, target string) error
+	// PushImage pushes a tagged image to the registry implied by target,
+	// returning the digest the registry assigned it.
+	PushImage(ctx context.Context, target string, auth RegistryAuth, logs io.Writer) (string, error)
+	// PullImage pulls ref from its registry, making it available to
+	// InspectImage and CreateContainer under that same reference.
+	PullImage(ctx context.Context, ref string, auth RegistryAuth, logs io.Writer) (Image, error)
+
+	// CreateContainer creates (but does not start) a container, returning its
+	// backend-assigned ID.
+	CreateContainer(ctx context.Context, config ContainerConfig) (string, error)
+	// StartContainer starts a previously created container.
+	StartContainer(ctx context.Context, id string) error
+	// WaitContainer blocks until the container exits and returns its exit
+	// code.
+	WaitContainer(ctx context.Context, id string) (int64, error)
+	// RemoveContainer removes a container known to the backend.
+	RemoveContainer(ctx context.Context, id string, force bool) error
+	// ContainerLogs returns a stream of the container's combined stdout and
+	// stderr.
+	ContainerLogs(ctx context.Context, id string) (io.ReadCloser, error)
+	// AttachContainer streams the container's stdout and stderr to the given
+	// writers, demultiplexed, until the container exits or ctx is canceled.
+	AttachContainer(ctx context.Context, id string, stdout, stderr io.Writer) error
+	// ContainerEvents streams lifecycle events for id. The returned channel
+	// is closed once the container exits or ctx is canceled.
+	ContainerEvents(ctx context.Context, id string) (<-chan ContainerEvent, error)
+}
+
+// Kind identifies a supported Backend implementation.
+type Kind string
+
+const (
+	// Docker selects the Docker Engine API backend.
+	Docker Kind = "docker"
+	// Podman selects the Podman libpod REST API backend.
+	Podman Kind = "podman"
+)
@@ -0,0 +1,208 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// dockerBackend implements Backend against a Docker daemon.
+type dockerBackend struct {
+	client *client.Client
+}
+
+// NewDockerBackend wraps an existing Docker client in a Backend.
+func NewDockerBackend(dockerClient *client.Client) Backend {
+	return &dockerBackend{client: dockerClient}
+}
+
+func (b *dockerBackend) BuildImage(ctx context.Context, opts BuildOptions, logs io.Writer) (Image, error) {
+	buildContext, err := archive.TarWithOptions(opts.ContextDir, &archive.TarOptions{})
+	if err != nil {
+		return Image{}, err
+	}
+	defer buildContext.Close()
+
+	response, err := b.client.ImageBuild(ctx, buildContext, dockerTypes.ImageBuildOptions{
+		Dockerfile: opts.Dockerfile,
+		Tags:       opts.Tags,
+	})
+	if err != nil {
+		return Image{}, err
+	}
+	defer response.Body.Close()
+
+	if _, err := io.Copy(logs, response.Body); err != nil {
+		return Image{}, err
+	}
+
+	if len(opts.Tags) == 0 {
+		return Image{}, nil
+	}
+	return b.InspectImage(ctx, opts.Tags[0])
+}
+
+func (b *dockerBackend) InspectImage(ctx context.Context, ref string) (Image, error) {
+	imageInfo, _, err := b.client.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return Image{}, err
+	}
+
+	var digest string
+	if len(imageInfo.RepoDigests) > 0 {
+		if _, d, ok := strings.Cut(imageInfo.RepoDigests[0], "@"); ok {
+			digest = d
+		}
+	}
+
+	return Image{ID: imageInfo.ID, Tags: imageInfo.RepoTags, Digest: digest}, nil
+}
+
+func (b *dockerBackend) RemoveImage(ctx context.Context, ref string, force bool) error {
+	_, err := b.client.ImageRemove(ctx, ref, dockerTypes.ImageRemoveOptions{Force: force, PruneChildren: true})
+	return err
+}
+
+func (b *dockerBackend) TagImage(ctx context.Context, source, target string) error {
+	return b.client.ImageTag(ctx, source, target)
+}
+
+func (b *dockerBackend) PushImage(ctx context.Context, target string, auth RegistryAuth, logs io.Writer) (string, error) {
+	response, err := b.client.ImagePush(ctx, target, dockerTypes.ImagePushOptions{RegistryAuth: auth.Encoded})
+	if err != nil {
+		return "", err
+	}
+	defer response.Close()
+
+	digest, err := digestFromJSONMessageStream(response, logs)
+	if err != nil {
+		return "", err
+	}
+	return digest, nil
+}
+
+func (b *dockerBackend) PullImage(ctx context.Context, ref string, auth RegistryAuth, logs io.Writer) (Image, error) {
+	response, err := b.client.ImagePull(ctx, ref, dockerTypes.ImagePullOptions{RegistryAuth: auth.Encoded})
+	if err != nil {
+		return Image{}, err
+	}
+	defer response.Close()
+
+	digest, err := digestFromJSONMessageStream(response, logs)
+	if err != nil {
+		return Image{}, err
+	}
+
+	image, err := b.InspectImage(ctx, ref)
+	if err != nil {
+		return Image{}, err
+	}
+	if image.Digest == "" {
+		image.Digest = digest
+	}
+	return image, nil
+}
+
+func (b *dockerBackend) CreateContainer(ctx context.Context, config ContainerConfig) (string, error) {
+	binds := make([]string, 0, len(config.Mounts))
+	for _, mount := range config.Mounts {
+		binds = append(binds, bindString(mount))
+	}
+
+	created, err := b.client.ContainerCreate(
+		ctx,
+		&container.Config{
+			Image: config.Image,
+			Env:   config.Env,
+			Cmd:   config.Command,
+		},
+		&container.HostConfig{Binds: binds},
+		nil,
+		nil,
+		"",
+	)
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+func (b *dockerBackend) StartContainer(ctx context.Context, id string) error {
+	return b.client.ContainerStart(ctx, id, dockerTypes.ContainerStartOptions{})
+}
+
+func (b *dockerBackend) WaitContainer(ctx context.Context, id string) (int64, error) {
+	return b.client.ContainerWait(ctx, id)
+}
+
+func (b *dockerBackend) RemoveContainer(ctx context.Context, id string, force bool) error {
+	return b.client.ContainerRemove(ctx, id, dockerTypes.ContainerRemoveOptions{Force: force})
+}
+
+func (b *dockerBackend) ContainerLogs(ctx context.Context, id string) (io.ReadCloser, error) {
+	return b.client.ContainerLogs(ctx, id, dockerTypes.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Follow: true})
+}
+
+func (b *dockerBackend) AttachContainer(ctx context.Context, id string, stdout, stderr io.Writer) error {
+	logs, err := b.client.ContainerLogs(ctx, id, dockerTypes.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Follow: true})
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	_, err = stdcopy.StdCopy(stdout, stderr, logs)
+	return err
+}
+
+func (b *dockerBackend) ContainerEvents(ctx context.Context, id string) (<-chan ContainerEvent, error) {
+	filterArgs := filters.NewArgs(filters.Arg("container", id))
+	messages, errs := b.client.Events(ctx, dockerTypes.EventsOptions{Filters: filterArgs})
+
+	events := make(chan ContainerEvent)
+	go func() {
+		defer close(events)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-errs:
+				if err != nil {
+					return
+				}
+			case message := <-messages:
+				event, ok := translateDockerEvent(ctx, b, id, message)
+				if !ok {
+					continue
+				}
+				events <- event
+				if event.Status == ContainerExited {
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func translateDockerEvent(ctx context.Context, b *dockerBackend, id string, message events.Message) (ContainerEvent, bool) {
+	switch message.Action {
+	case "create":
+		return ContainerEvent{Status: ContainerCreated}, true
+	case "start":
+		return ContainerEvent{Status: ContainerStarted}, true
+	case "die":
+		exitCode, _ := b.client.ContainerWait(ctx, id)
+		return ContainerEvent{Status: ContainerExited, ExitCode: exitCode}, true
+	default:
+		return ContainerEvent{}, false
+	}
+}
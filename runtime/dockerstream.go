@@ -0,0 +1,43 @@
+package runtime
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// digestFromJSONMessageStream copies a Docker push/pull's streamed JSON
+// progress messages to logs and extracts the image digest from the last
+// status line containing "digest: " (the summary line Docker prints at the
+// end of a push). The match is case-sensitive and Aux fields are not read,
+// so a pull's "Digest: " status line yields no digest and "" is returned.
+func digestFromJSONMessageStream(stream io.Reader, logs io.Writer) (string, error) {
+	decoder := json.NewDecoder(stream)
+	digest := ""
+
+	for {
+		var message jsonmessage.JSONMessage
+		if err := decoder.Decode(&message); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+
+		if message.Error != nil {
+			return "", message.Error
+		}
+
+		if logs != nil && message.Status != "" {
+			io.WriteString(logs, message.Status+"\n")
+		}
+
+		if idx := strings.Index(message.Status, "digest: "); idx != -1 {
+			digest = strings.Fields(message.Status[idx+len("digest: "):])[0]
+		}
+	}
+
+	return digest, nil
+}
@@ -0,0 +1,40 @@
+package runtime
+
+import "strings"
+
+// bindString renders a Mount as a Docker HostConfig.Binds entry
+// ("source:target", optionally followed by ":ro", ":z"/":Z" or ":ro,z"/":ro,Z").
+func bindString(mount Mount) string {
+	var suffix []string
+	if mount.ReadOnly {
+		suffix = append(suffix, "ro")
+	}
+	switch mount.SELinuxRelabel {
+	case SELinuxRelabelShared:
+		suffix = append(suffix, "z")
+	case SELinuxRelabelPrivate:
+		suffix = append(suffix, "Z")
+	}
+
+	bind := mount.Source + ":" + mount.Target
+	if len(suffix) > 0 {
+		bind += ":" + strings.Join(suffix, ",")
+	}
+	return bind
+}
+
+// mountOptions renders a Mount's ReadOnly/SELinuxRelabel fields as the
+// []string Options libpod's specgen.Mount expects.
+func mountOptions(mount Mount) []string {
+	var options []string
+	if mount.ReadOnly {
+		options = append(options, "ro")
+	}
+	switch mount.SELinuxRelabel {
+	case SELinuxRelabelShared:
+		options = append(options, "z")
+	case SELinuxRelabelPrivate:
+		options = append(options, "Z")
+	}
+	return options
+}
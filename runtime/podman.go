@@ -0,0 +1,316 @@
+package runtime
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	dockerTypes "github.com/docker/docker/api/types"
+
+	"github.com/containers/podman/v4/pkg/bindings/containers"
+	"github.com/containers/podman/v4/pkg/bindings/images"
+	"github.com/containers/podman/v4/pkg/bindings/system"
+	"github.com/containers/podman/v4/pkg/domain/entities"
+	"github.com/containers/podman/v4/pkg/specgen"
+)
+
+// podmanBackend implements Backend against the libpod REST API, reachable
+// over the Podman service's unix socket (set up via `podman system service`
+// or, on rootless hosts, the per-user systemd socket).
+type podmanBackend struct {
+	// conn is a context.Context carrying the bindings connection, as
+	// returned by bindings.NewConnection. It is threaded through as the ctx
+	// argument to every bindings call rather than stored separately, which
+	// is the pattern the bindings package itself expects.
+	conn context.Context
+}
+
+// NewPodmanBackend wraps a libpod bindings connection (produced by
+// bindings.NewConnection) in a Backend.
+func NewPodmanBackend(conn context.Context) Backend {
+	return &podmanBackend{conn: conn}
+}
+
+func (b *podmanBackend) BuildImage(ctx context.Context, opts BuildOptions, logs io.Writer) (Image, error) {
+	report, err := images.Build(b.conn, []string{opts.Dockerfile}, entities.BuildOptions{
+		ContextDirectory: opts.ContextDir,
+	})
+	if err != nil {
+		return Image{}, err
+	}
+
+	for _, tag := range opts.Tags {
+		if err := images.Tag(b.conn, report.ID, tag, ""); err != nil {
+			return Image{}, err
+		}
+	}
+
+	return b.InspectImage(ctx, report.ID)
+}
+
+func (b *podmanBackend) InspectImage(ctx context.Context, ref string) (Image, error) {
+	data, err := images.GetImage(b.conn, ref, nil)
+	if err != nil {
+		return Image{}, err
+	}
+	return Image{ID: data.ID, Tags: data.RepoTags}, nil
+}
+
+func (b *podmanBackend) RemoveImage(ctx context.Context, ref string, force bool) error {
+	_, errs := images.Remove(b.conn, []string{ref}, &images.RemoveOptions{Force: &force})
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+func (b *podmanBackend) TagImage(ctx context.Context, source, target string) error {
+	return images.Tag(b.conn, source, target, "")
+}
+
+func (b *podmanBackend) PushImage(ctx context.Context, target string, auth RegistryAuth, logs io.Writer) (string, error) {
+	authFile, cleanup, err := authFileFor(auth)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	report, err := images.Push(b.conn, target, target, &images.PushOptions{
+		Authfile: authFile,
+		Progress: progressChan(logs),
+	})
+	if err != nil {
+		return "", err
+	}
+	return report, nil
+}
+
+func (b *podmanBackend) PullImage(ctx context.Context, ref string, auth RegistryAuth, logs io.Writer) (Image, error) {
+	authFile, cleanup, err := authFileFor(auth)
+	if err != nil {
+		return Image{}, err
+	}
+	defer cleanup()
+
+	if _, err := images.Pull(b.conn, ref, &images.PullOptions{
+		Authfile: authFile,
+		Progress: progressChan(logs),
+	}); err != nil {
+		return Image{}, err
+	}
+	return b.InspectImage(ctx, ref)
+}
+
+// authFileFor adapts the Docker-style base64 AuthConfig JSON Simplex plumbs
+// through RegistryAuth.Encoded (the same convention builds.authForRef
+// produces for the Docker backend) into a temporary authfile on disk, which
+// is the form libpod's Authfile option actually expects. The returned
+// cleanup func removes that temp file once the caller is done with it; it is
+// a no-op, and the returned path nil, when auth carries no credentials.
+func authFileFor(auth RegistryAuth) (*string, func(), error) {
+	noop := func() {}
+	if auth.Encoded == "" {
+		return nil, noop, nil
+	}
+
+	decoded, err := base64.URLEncoding.DecodeString(auth.Encoded)
+	if err != nil {
+		return nil, noop, fmt.Errorf("could not decode registry auth: %w", err)
+	}
+
+	var authConfig dockerTypes.AuthConfig
+	if err := json.Unmarshal(decoded, &authConfig); err != nil {
+		return nil, noop, fmt.Errorf("could not parse registry auth: %w", err)
+	}
+
+	authFile, err := ioutil.TempFile("", "simplex-authfile-")
+	if err != nil {
+		return nil, noop, err
+	}
+	defer authFile.Close()
+
+	creds := base64.StdEncoding.EncodeToString([]byte(authConfig.Username + ":" + authConfig.Password))
+	config := struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}{
+		Auths: map[string]struct {
+			Auth string `json:"auth"`
+		}{
+			authConfig.ServerAddress: {Auth: creds},
+		},
+	}
+	if err := json.NewEncoder(authFile).Encode(config); err != nil {
+		return nil, noop, err
+	}
+
+	path := authFile.Name()
+	return &path, func() { os.Remove(path) }, nil
+}
+
+// progressChan drains libpod's progress channel into logs if requested; a
+// nil logs writer means the caller does not want progress output.
+func progressChan(logs io.Writer) chan images.ProgressReport {
+	if logs == nil {
+		return nil
+	}
+	ch := make(chan images.ProgressReport)
+	go func() {
+		for report := range ch {
+			io.WriteString(logs, report.Text()+"\n")
+		}
+	}()
+	return ch
+}
+
+func (b *podmanBackend) CreateContainer(ctx context.Context, config ContainerConfig) (string, error) {
+	spec := specgen.NewSpecGenerator(config.Image, false)
+	spec.Env = map[string]string{}
+	for _, env := range config.Env {
+		if key, value, ok := strings.Cut(env, "="); ok {
+			spec.Env[key] = value
+		}
+	}
+	for _, mount := range config.Mounts {
+		spec.Mounts = append(spec.Mounts, specgen.Mount{
+			Source:      mount.Source,
+			Destination: mount.Target,
+			Type:        "bind",
+			Options:     mountOptions(mount),
+		})
+	}
+	spec.Command = config.Command
+
+	created, err := containers.CreateWithSpec(b.conn, spec, nil)
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+func (b *podmanBackend) StartContainer(ctx context.Context, id string) error {
+	return containers.Start(b.conn, id, nil)
+}
+
+func (b *podmanBackend) WaitContainer(ctx context.Context, id string) (int64, error) {
+	exitCode, err := containers.Wait(b.conn, id, nil)
+	return int64(exitCode), err
+}
+
+func (b *podmanBackend) RemoveContainer(ctx context.Context, id string, force bool) error {
+	return containers.Remove(b.conn, id, &containers.RemoveOptions{Force: &force})
+}
+
+func (b *podmanBackend) ContainerLogs(ctx context.Context, id string) (io.ReadCloser, error) {
+	reader, writer := io.Pipe()
+	stdout := make(chan string)
+	stderr := make(chan string)
+
+	go func() {
+		defer writer.Close()
+		for {
+			select {
+			case line, ok := <-stdout:
+				if !ok {
+					return
+				}
+				io.WriteString(writer, line+"\n")
+			case line, ok := <-stderr:
+				if !ok {
+					return
+				}
+				io.WriteString(writer, line+"\n")
+			}
+		}
+	}()
+
+	go containers.Logs(b.conn, id, &containers.LogOptions{Follow: boolPtr(true)}, stdout, stderr)
+
+	return reader, nil
+}
+
+func boolPtr(v bool) *bool { return &v }
+
+func (b *podmanBackend) AttachContainer(ctx context.Context, id string, stdout, stderr io.Writer) error {
+	stdoutCh := make(chan string)
+	stderrCh := make(chan string)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- containers.Logs(b.conn, id, &containers.LogOptions{Follow: boolPtr(true)}, stdoutCh, stderrCh)
+	}()
+
+	for {
+		select {
+		case line, ok := <-stdoutCh:
+			if !ok {
+				stdoutCh = nil
+				continue
+			}
+			io.WriteString(stdout, line+"\n")
+		case line, ok := <-stderrCh:
+			if !ok {
+				stderrCh = nil
+				continue
+			}
+			io.WriteString(stderr, line+"\n")
+		case err := <-done:
+			return err
+		}
+	}
+}
+
+func (b *podmanBackend) ContainerEvents(ctx context.Context, id string) (<-chan ContainerEvent, error) {
+	libpodEvents := make(chan entities.Event)
+	cancel := make(chan bool)
+
+	go system.Events(b.conn, libpodEvents, cancel, &system.EventsOptions{ //nolint:errcheck
+		Filters: map[string][]string{"container": {id}},
+	})
+
+	events := make(chan ContainerEvent)
+	go func() {
+		defer close(events)
+		for {
+			select {
+			case <-ctx.Done():
+				close(cancel)
+				return
+			case raw, ok := <-libpodEvents:
+				if !ok {
+					return
+				}
+				event, match := translatePodmanEvent(raw)
+				if !match {
+					continue
+				}
+				events <- event
+				if event.Status == ContainerExited {
+					close(cancel)
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func translatePodmanEvent(raw entities.Event) (ContainerEvent, bool) {
+	switch raw.Status {
+	case "create":
+		return ContainerEvent{Status: ContainerCreated}, true
+	case "start":
+		return ContainerEvent{Status: ContainerStarted}, true
+	case "died":
+		return ContainerEvent{Status: ContainerExited, ExitCode: int64(raw.ContainerExitCode)}, true
+	default:
+		return ContainerEvent{}, false
+	}
+}
@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	podmanBindings "github.com/containers/podman/v4/pkg/bindings"
+	dockerClient "github.com/docker/docker/client"
+)
+
+// EnvVar is the environment variable used to select a runtime backend when
+// no explicit kind is given. It takes precedence over the Docker-only
+// default but is overridden by an explicit flag value.
+const EnvVar = "SIMPLEX_RUNTIME"
+
+// Resolve determines which backend kind to use, preferring an explicit kind
+// (typically sourced from a CLI flag) over the SIMPLEX_RUNTIME environment
+// variable, and finally falling back to Docker for backwards compatibility.
+func Resolve(kind string) Kind {
+	if kind != "" {
+		return Kind(kind)
+	}
+	if env := os.Getenv(EnvVar); env != "" {
+		return Kind(env)
+	}
+	return Docker
+}
+
+// New constructs the Backend for the given Kind, connecting to the local
+// Docker daemon or Podman service as appropriate.
+func New(ctx context.Context, kind Kind) (Backend, error) {
+	switch kind {
+	case Docker, "":
+		client, err := dockerClient.NewClientWithOpts(dockerClient.FromEnv, dockerClient.WithAPIVersionNegotiation())
+		if err != nil {
+			return nil, fmt.Errorf("could not create docker client: %w", err)
+		}
+		return NewDockerBackend(client), nil
+	case Podman:
+		conn, err := podmanBindings.NewConnection(ctx, os.Getenv("PODMAN_HOST"))
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to podman service: %w", err)
+		}
+		return NewPodmanBackend(conn), nil
+	default:
+		return nil, fmt.Errorf("unsupported runtime backend: %q", kind)
+	}
+}
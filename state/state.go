@@ -0,0 +1,97 @@
+// Package state manages the on-disk Simplex state directory, including the
+// sqlite database that tracks components, builds and executions.
+package state
+
+import (
+	"database/sql"
+	"os"
+	"path"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// DBFileName is the name of the sqlite database file Simplex keeps inside its
+// state directory.
+const DBFileName = "simplex.db"
+
+// DefaultDir returns the state directory Simplex uses when the caller (the
+// CLI, typically) has not been pointed at a specific one: $SIMPLEX_STATE_DIR
+// if set, otherwise ~/.simplex.
+func DefaultDir() (string, error) {
+	if dir := os.Getenv("SIMPLEX_STATE_DIR"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(home, ".simplex"), nil
+}
+
+// schema holds the DDL statements required to bring a fresh state database up
+// to date. Statements are applied in order and are all idempotent (CREATE
+// TABLE IF NOT EXISTS) so that Init can be called safely against an existing
+// state directory.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS components (
+		id TEXT PRIMARY KEY,
+		component_type TEXT NOT NULL,
+		component_path TEXT NOT NULL,
+		specification_path TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE IF NOT EXISTS builds (
+		id TEXT PRIMARY KEY,
+		component_id TEXT REFERENCES components(id),
+		remote_ref TEXT,
+		digest TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE IF NOT EXISTS executions (
+		id TEXT PRIMARY KEY,
+		build_id TEXT NOT NULL REFERENCES builds(id),
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE IF NOT EXISTS workflow_runs (
+		id TEXT PRIMARY KEY,
+		spec_path TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE TABLE IF NOT EXISTS workflow_run_nodes (
+		workflow_run_id TEXT NOT NULL REFERENCES workflow_runs(id),
+		node_id TEXT NOT NULL,
+		execution_id TEXT REFERENCES executions(id),
+		status TEXT NOT NULL,
+		error TEXT,
+		PRIMARY KEY (workflow_run_id, node_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS execution_states (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		execution_id TEXT NOT NULL REFERENCES executions(id),
+		state TEXT NOT NULL,
+		exit_code INTEGER,
+		observed_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`,
+}
+
+// Init creates the given directory (if it does not already exist) and
+// initializes the sqlite database Simplex uses to track its state inside it.
+func Init(stateDir string) error {
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite3", path.Join(stateDir, DBFileName))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for _, statement := range schema {
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package workflows
+
+import "fmt"
+
+// graph is the validated, in-memory representation of a Spec: nodes indexed
+// by ID along with the edges pointing into and out of each one.
+type graph struct {
+	nodes     map[string]Node
+	outEdges  map[string][]Edge // keyed by FromNode
+	dependsOn map[string][]Edge // keyed by ToNode
+}
+
+// buildGraph validates a Spec (no duplicate node IDs, no edges referencing
+// unknown nodes or undeclared inputs/outputs, no cycles) and returns its
+// graph representation.
+func buildGraph(spec Spec) (*graph, error) {
+	g := &graph{
+		nodes:     map[string]Node{},
+		outEdges:  map[string][]Edge{},
+		dependsOn: map[string][]Edge{},
+	}
+
+	for _, node := range spec.Nodes {
+		if _, exists := g.nodes[node.ID]; exists {
+			return nil, fmt.Errorf("duplicate node ID: %s", node.ID)
+		}
+		g.nodes[node.ID] = node
+	}
+
+	for _, edge := range spec.Edges {
+		fromNode, ok := g.nodes[edge.FromNode]
+		if !ok {
+			return nil, fmt.Errorf("edge references unknown node: %s", edge.FromNode)
+		}
+		if _, ok := fromNode.Outputs[edge.FromOutput]; !ok {
+			return nil, fmt.Errorf("node %s has no output named %s", edge.FromNode, edge.FromOutput)
+		}
+
+		toNode, ok := g.nodes[edge.ToNode]
+		if !ok {
+			return nil, fmt.Errorf("edge references unknown node: %s", edge.ToNode)
+		}
+		if _, ok := toNode.Inputs[edge.ToInput]; !ok {
+			return nil, fmt.Errorf("node %s has no input named %s", edge.ToNode, edge.ToInput)
+		}
+
+		g.outEdges[edge.FromNode] = append(g.outEdges[edge.FromNode], edge)
+		g.dependsOn[edge.ToNode] = append(g.dependsOn[edge.ToNode], edge)
+	}
+
+	if _, err := g.topologicalOrder(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// topologicalOrder returns the node IDs of the graph in an order consistent
+// with their dependencies, or an error if the graph contains a cycle.
+func (g *graph) topologicalOrder() ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[string]int{}
+	order := make([]string, 0, len(g.nodes))
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("workflow graph contains a cycle through node %s", id)
+		}
+
+		state[id] = visiting
+		for _, edge := range g.dependsOn[id] {
+			if err := visit(edge.FromNode); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		order = append(order, id)
+		return nil
+	}
+
+	for id := range g.nodes {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,177 @@
+package workflows
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/simiotics/simplex/executions"
+	"github.com/simiotics/simplex/runtime"
+)
+
+// Run is a record of a single execution of a workflow graph.
+type Run struct {
+	ID       string
+	SpecPath string
+}
+
+// NodeResult carries the outcome of running a single node within a workflow.
+type NodeResult struct {
+	NodeID      string
+	ExecutionID string
+	Err         error
+}
+
+// errUpstreamFailed is recorded against a node that was never attempted
+// because one of its dependencies failed.
+var errUpstreamFailed = fmt.Errorf("upstream node failed")
+
+// Execute runs every node in spec's graph against backend, using componentID
+// for a node as a lookup into components previously built via builds.CreateBuild
+// (the caller is expected to have already produced a build per component ID,
+// passed in via builtImages). Nodes with no unfinished dependency run
+// concurrently; a node whose dependency failed is itself marked failed
+// without being started, but its siblings continue unaffected.
+func Execute(ctx context.Context, db *sql.DB, backend runtime.Backend, specPath string, spec Spec, builtImages map[string]string) (Run, []NodeResult, error) {
+	g, err := buildGraph(spec)
+	if err != nil {
+		return Run{}, nil, err
+	}
+
+	workflowDir, err := ioutil.TempDir("", "simplex-workflow-")
+	if err != nil {
+		return Run{}, nil, fmt.Errorf("could not create workflow tempdir: %w", err)
+	}
+
+	run := Run{ID: path.Base(workflowDir), SpecPath: specPath}
+	if _, err := db.Exec("INSERT INTO workflow_runs (id, spec_path) VALUES (?, ?)", run.ID, run.SpecPath); err != nil {
+		return Run{}, nil, err
+	}
+
+	// outputPaths holds, for every (node, output name), the host-side file
+	// that output is materialized to within the workflow's tempdir. Each
+	// file is created up front (rather than left for the backend to create
+	// as a bind-mount source) so that it comes into the producing
+	// container as a file, not a directory.
+	outputPaths := map[string]string{}
+	for _, node := range g.nodes {
+		for outputName := range node.Outputs {
+			outputPath := path.Join(workflowDir, node.ID+"-"+outputName)
+			outputFile, err := os.Create(outputPath)
+			if err != nil {
+				return Run{}, nil, fmt.Errorf("could not create output file %s: %w", outputPath, err)
+			}
+			outputFile.Close()
+			outputPaths[outputKey(node.ID, outputName)] = outputPath
+		}
+	}
+
+	done := map[string]chan struct{}{}
+	for id := range g.nodes {
+		done[id] = make(chan struct{})
+	}
+
+	results := make([]NodeResult, len(g.nodes))
+	var resultsMu sync.Mutex
+	var wg sync.WaitGroup
+
+	i := 0
+	indexOf := map[string]int{}
+	for id := range g.nodes {
+		indexOf[id] = i
+		i++
+	}
+
+	for _, node := range g.nodes {
+		wg.Add(1)
+		go func(node Node) {
+			defer wg.Done()
+			defer close(done[node.ID])
+
+			result := NodeResult{NodeID: node.ID}
+
+			for _, edge := range g.dependsOn[node.ID] {
+				<-done[edge.FromNode]
+			}
+			for _, edge := range g.dependsOn[node.ID] {
+				resultsMu.Lock()
+				upstream := results[indexOf[edge.FromNode]]
+				resultsMu.Unlock()
+				if upstream.Err != nil {
+					result.Err = errUpstreamFailed
+				}
+			}
+
+			if result.Err == nil {
+				var mounts []executions.Mount
+				for outputName, mountpoint := range node.Outputs {
+					mounts = append(mounts, executions.Mount{
+						Source: outputPaths[outputKey(node.ID, outputName)],
+						Target: mountpoint,
+					})
+				}
+				for _, edge := range g.dependsOn[node.ID] {
+					mounts = append(mounts, executions.Mount{
+						Source: outputPaths[outputKey(edge.FromNode, edge.FromOutput)],
+						Target: node.Inputs[edge.ToInput],
+					})
+				}
+
+				buildID, ok := builtImages[node.ComponentID]
+				if !ok {
+					result.Err = fmt.Errorf("no build available for component %s", node.ComponentID)
+				} else {
+					execution, err := executions.Execute(ctx, db, backend, buildID, "", mounts)
+					if err != nil {
+						result.Err = err
+					} else {
+						exitCode, err := backend.WaitContainer(ctx, execution.ID)
+						if err != nil {
+							result.Err = err
+						} else if exitCode != 0 {
+							result.Err = fmt.Errorf("component %s exited with code %d", node.ComponentID, exitCode)
+						}
+						result.ExecutionID = execution.ID
+					}
+				}
+			}
+
+			recordNodeResult(db, run.ID, result)
+
+			resultsMu.Lock()
+			results[indexOf[node.ID]] = result
+			resultsMu.Unlock()
+		}(node)
+	}
+
+	wg.Wait()
+
+	return run, results, nil
+}
+
+func outputKey(nodeID, outputName string) string {
+	return nodeID + "/" + outputName
+}
+
+func recordNodeResult(db *sql.DB, runID string, result NodeResult) {
+	status := "succeeded"
+	var errMsg interface{}
+	if result.Err != nil {
+		status = "failed"
+		errMsg = result.Err.Error()
+	}
+
+	var executionID interface{}
+	if result.ExecutionID != "" {
+		executionID = result.ExecutionID
+	}
+
+	db.Exec(
+		"INSERT INTO workflow_run_nodes (workflow_run_id, node_id, execution_id, status, error) VALUES (?, ?, ?, ?, ?)",
+		runID, result.NodeID, executionID, status, errMsg,
+	)
+}
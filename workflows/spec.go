@@ -0,0 +1,48 @@
+// Package workflows wires together multiple Simplex components into a
+// directed acyclic graph, running each as its own execution and threading
+// file-based outputs of upstream components into the inputs of downstream
+// ones.
+package workflows
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Node is a single component invocation within a workflow. Inputs and
+// Outputs map a locally-scoped name to the mountpoint the component's
+// specification expects that named input/output to appear at inside its
+// container.
+type Node struct {
+	ID          string            `yaml:"id" json:"id"`
+	ComponentID string            `yaml:"component" json:"component"`
+	Inputs      map[string]string `yaml:"inputs" json:"inputs"`
+	Outputs     map[string]string `yaml:"outputs" json:"outputs"`
+}
+
+// Edge wires a named output of one node to a named input of another.
+type Edge struct {
+	FromNode   string `yaml:"from_node" json:"from_node"`
+	FromOutput string `yaml:"from_output" json:"from_output"`
+	ToNode     string `yaml:"to_node" json:"to_node"`
+	ToInput    string `yaml:"to_input" json:"to_input"`
+}
+
+// Spec is the parsed form of a workflow specification document.
+type Spec struct {
+	Nodes []Node `yaml:"nodes" json:"nodes"`
+	Edges []Edge `yaml:"edges" json:"edges"`
+}
+
+// ReadSpec parses a workflow specification. The format is YAML, which is a
+// superset of JSON, so either is accepted.
+func ReadSpec(reader io.Reader) (Spec, error) {
+	var spec Spec
+	decoder := yaml.NewDecoder(reader)
+	if err := decoder.Decode(&spec); err != nil {
+		return Spec{}, fmt.Errorf("could not parse workflow specification: %w", err)
+	}
+	return spec, nil
+}